@@ -73,109 +73,9 @@ func isSuperset(ss, a api.Affinity) bool {
 	return true
 }
 
-// isNodeAffinitySuperset checks if ss is a superset of a.
-func isNodeAffinitySuperset(ss, a *api.NodeAffinity) bool {
-	if ss == nil {
-		return true
-	}
-	if a == nil {
-		return false
-	}
-	// A full implementation would be needed to check for supersets.
-	// For now, we'll consider them equal.
-	return apiequality.Semantic.DeepEqual(ss, a)
-}
-
-// isPodAffinitySuperset checks if ss is a superset of a.
-func isPodAffinitySuperset(ss, a *api.PodAffinity) bool {
-	if ss == nil {
-		return true
-	}
-	if a == nil {
-		return false
-	}
-	// A full implementation would be needed to check for supersets.
-	// For now, we'll consider them equal.
-	return apiequality.Semantic.DeepEqual(ss, a)
-}
-
-// isPodAntiAffinitySuperset checks if ss is a superset of a.
-// For anti-affinity, superset means it is *less* restrictive.
-func isPodAntiAffinitySuperset(ss, a *api.PodAntiAffinity) bool {
-	if ss == nil {
-		return true
-	}
-	if a == nil {
-		return false
-	}
-	// A full implementation would be needed to check for supersets.
-	// For now, we'll consider them equal.
-	return apiequality.Semantic.DeepEqual(ss, a)
-}
-
-// MergePodAffinities merges two affinity objects.
-// It merges each field of the affinity struct.
-// For NodeAffinity, PodAffinity, and PodAntiAffinity, it merges the
-// Required and Preferred terms by appending them.
+// MergePodAffinities merges two affinity objects, appending the default's terms
+// onto the pod's. It is a thin wrapper around MergePodAffinitiesWithStrategy using
+// StrategyAppend, kept for existing callers.
 func MergePodAffinities(podAffinity, defaultAffinity *api.Affinity) *api.Affinity {
-	if defaultAffinity == nil {
-		return podAffinity
-	}
-	if podAffinity == nil {
-		return defaultAffinity
-	}
-
-	merged := podAffinity.DeepCopy()
-
-	// Merge NodeAffinity
-	if defaultAffinity.NodeAffinity != nil {
-		if merged.NodeAffinity == nil {
-			merged.NodeAffinity = defaultAffinity.NodeAffinity.DeepCopy()
-		} else {
-			// Merge RequiredDuringSchedulingIgnoredDuringExecution
-			if defaultAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
-				if merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
-					merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = defaultAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.DeepCopy()
-				} else {
-					merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms =
-						append(merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms,
-							defaultAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms...)
-				}
-			}
-			// Merge PreferredDuringSchedulingIgnoredDuringExecution
-			merged.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution =
-				append(merged.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-					defaultAffinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
-		}
-	}
-
-	// Merge PodAffinity
-	if defaultAffinity.PodAffinity != nil {
-		if merged.PodAffinity == nil {
-			merged.PodAffinity = defaultAffinity.PodAffinity.DeepCopy()
-		} else {
-			merged.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution =
-				append(merged.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-					defaultAffinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
-			merged.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution =
-				append(merged.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-					defaultAffinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
-		}
-	}
-
-	// Merge PodAntiAffinity
-	if defaultAffinity.PodAntiAffinity != nil {
-		if merged.PodAntiAffinity == nil {
-			merged.PodAntiAffinity = defaultAffinity.PodAntiAffinity.DeepCopy()
-		} else {
-			merged.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution =
-				append(merged.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-					defaultAffinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
-			merged.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution =
-				append(merged.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-					defaultAffinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
-		}
-	}
-
-	return merged
+	return MergePodAffinitiesWithStrategy(podAffinity, defaultAffinity, StrategyAppend)
 }
@@ -151,5 +151,174 @@ func TestMergePodAffinities(t *testing.T) {
 }
 
 func TestIsSuperset(t *testing.T) {
-	t.Skip("isSuperset is not fully implemented yet")
+	tests := []struct {
+		name string
+		ss   api.Affinity
+		a    api.Affinity
+		want bool
+	}{
+		{
+			name: "NodeAffinity In values superset",
+			ss:   nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1", "us-west-1")),
+			a:    nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1")),
+			want: true,
+		},
+		{
+			name: "NodeAffinity In values not a superset",
+			ss:   nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1")),
+			a:    nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1", "us-west-1")),
+			want: false,
+		},
+		{
+			name: "NodeAffinity NotIn values superset when ss excludes fewer",
+			ss:   nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpNotIn, "us-east-1")),
+			a:    nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpNotIn, "us-east-1", "us-west-1")),
+			want: true,
+		},
+		{
+			name: "NodeAffinity Exists is implied by In",
+			ss:   nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpExists)),
+			a:    nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1")),
+			want: true,
+		},
+		{
+			name: "NodeAffinity DoesNotExist only covered by DoesNotExist",
+			ss:   nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpDoesNotExist)),
+			a:    nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1")),
+			want: false,
+		},
+		{
+			name: "NodeAffinity Gt lower threshold is a superset",
+			ss:   nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpGt, "2")),
+			a:    nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpGt, "4")),
+			want: true,
+		},
+		{
+			name: "NodeAffinity Gt higher threshold is not a superset",
+			ss:   nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpGt, "6")),
+			a:    nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpGt, "4")),
+			want: false,
+		},
+		{
+			name: "NodeAffinity Lt higher threshold is a superset",
+			ss:   nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpLt, "8")),
+			a:    nodeAffinityOf(nodeReq("cpus", api.NodeSelectorOpLt, "4")),
+			want: true,
+		},
+		{
+			name: "PodAffinity broader ss label selector is a superset",
+			ss: api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			a: api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1", "env": "prod"}}},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "PodAffinity mismatched topology key is not a superset",
+			ss: api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "region", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			a: api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "PodAntiAffinity fewer ss terms is a superset",
+			ss: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			a: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s2"}}},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "PodAntiAffinity broader ss term is not a superset",
+			ss: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			a: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1", "env": "prod"}}},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "PodAntiAffinity ss term dominated by broader a term",
+			ss: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1", "env": "prod"}}},
+					},
+				},
+			},
+			a: api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isSuperset(test.ss, test.a); got != test.want {
+				t.Errorf("isSuperset(%+v, %+v) = %v, want %v", test.ss, test.a, got, test.want)
+			}
+		})
+	}
+}
+
+func nodeReq(key string, op api.NodeSelectorOperator, values ...string) api.NodeSelectorRequirement {
+	return api.NodeSelectorRequirement{Key: key, Operator: op, Values: values}
+}
+
+func nodeAffinityOf(reqs ...api.NodeSelectorRequirement) api.Affinity {
+	return api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: reqs},
+				},
+			},
+		},
+	}
 }
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"fmt"
+	"sort"
+
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// CanonicalizeAffinity returns a deep copy of a with its requirement and term
+// slices sorted into a deterministic order (by key, then operator, then sorted
+// values), so that two semantically-equal affinities produced in different orders
+// compare equal and MergeAffinities' superset/dedup logic produces stable output.
+// Node selector requirements are sorted inside each term; pod affinity terms are
+// only sorted as a list, and their label selector expressions and Namespaces keep
+// their original order.
+func CanonicalizeAffinity(a *api.Affinity) *api.Affinity {
+	if a == nil {
+		return nil
+	}
+	out := a.DeepCopy()
+	canonicalizeNodeAffinity(out.NodeAffinity)
+	if out.PodAffinity != nil {
+		canonicalizePodAffinityTerms(out.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		canonicalizeWeightedPodAffinityTerms(out.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	if out.PodAntiAffinity != nil {
+		canonicalizePodAffinityTerms(out.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		canonicalizeWeightedPodAffinityTerms(out.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	return out
+}
+
+func canonicalizeNodeAffinity(na *api.NodeAffinity) {
+	if na == nil {
+		return
+	}
+	if req := na.RequiredDuringSchedulingIgnoredDuringExecution; req != nil {
+		for i, t := range req.NodeSelectorTerms {
+			req.NodeSelectorTerms[i] = canonicalNodeSelectorTerm(t)
+		}
+		sort.Slice(req.NodeSelectorTerms, func(i, j int) bool {
+			return nodeSelectorTermKey(req.NodeSelectorTerms[i]) < nodeSelectorTermKey(req.NodeSelectorTerms[j])
+		})
+	}
+
+	prefs := na.PreferredDuringSchedulingIgnoredDuringExecution
+	for i := range prefs {
+		prefs[i].Preference = canonicalNodeSelectorTerm(prefs[i].Preference)
+	}
+	sort.Slice(prefs, func(i, j int) bool {
+		if prefs[i].Weight != prefs[j].Weight {
+			return prefs[i].Weight < prefs[j].Weight
+		}
+		return nodeSelectorTermKey(prefs[i].Preference) < nodeSelectorTermKey(prefs[j].Preference)
+	})
+}
+
+func nodeSelectorTermKey(t api.NodeSelectorTerm) string {
+	return fmt.Sprintf("%+v", t)
+}
+
+func canonicalizePodAffinityTerms(terms []api.PodAffinityTerm) {
+	sort.Slice(terms, func(i, j int) bool {
+		return podAffinityTermKey(terms[i]) < podAffinityTermKey(terms[j])
+	})
+}
+
+func canonicalizeWeightedPodAffinityTerms(terms []api.WeightedPodAffinityTerm) {
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Weight != terms[j].Weight {
+			return terms[i].Weight < terms[j].Weight
+		}
+		return podAffinityTermKey(terms[i].PodAffinityTerm) < podAffinityTermKey(terms[j].PodAffinityTerm)
+	})
+}
+
+func podAffinityTermKey(t api.PodAffinityTerm) string {
+	return fmt.Sprintf("%+v", t)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestCanonicalizeAffinity(t *testing.T) {
+	a := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{
+						{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-west-1", "us-east-1"}},
+					}},
+				},
+			},
+		},
+	}
+	b := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{
+						{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1", "us-west-1"}},
+					}},
+				},
+			},
+		},
+	}
+
+	ca, cb := CanonicalizeAffinity(a), CanonicalizeAffinity(b)
+	if !apiequality.Semantic.DeepEqual(ca, cb) {
+		t.Errorf("CanonicalizeAffinity should produce equal output for equivalent input in different order:\n%+v\n%+v", ca, cb)
+	}
+
+	// The original affinities should be left untouched (CanonicalizeAffinity
+	// operates on a copy).
+	if a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values[0] != "us-west-1" {
+		t.Errorf("CanonicalizeAffinity must not mutate its input")
+	}
+}
+
+func TestCanonicalizeAffinityTermOrder(t *testing.T) {
+	a := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}}}},
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "disk", Operator: api.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+				},
+			},
+		},
+	}
+	b := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "disk", Operator: api.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}}}},
+				},
+			},
+		},
+	}
+
+	ca, cb := CanonicalizeAffinity(a), CanonicalizeAffinity(b)
+	if !apiequality.Semantic.DeepEqual(ca, cb) {
+		t.Errorf("CanonicalizeAffinity should be insensitive to term order:\n%+v\n%+v", ca, cb)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// ContainsAffinity reports whether outer is a superset of inner, i.e. whether
+// outer's required terms are no more restrictive than inner's, by the same rules
+// isSuperset applies. Preferred terms are not compared, and an outer that is nil
+// or has no required terms contains every inner.
+func ContainsAffinity(outer, inner *api.Affinity) bool {
+	var o, i api.Affinity
+	if outer != nil {
+		o = *outer
+	}
+	if inner != nil {
+		i = *inner
+	}
+	return isNodeAffinitySuperset(o.NodeAffinity, i.NodeAffinity) &&
+		isPodAffinitySuperset(o.PodAffinity, i.PodAffinity) &&
+		isPodAntiAffinitySuperset(o.PodAntiAffinity, i.PodAntiAffinity)
+}
+
+// SubtractAffinity returns a with every required or preferred term removed that a
+// term in b already covers: for node and pod affinity, a term of b that is no more
+// restrictive; for pod anti-affinity, a term of b that is at least as restrictive.
+// It is the inverse of MergePodAffinities: a
+// defaulter can use it to idempotently add a default affinity by merging in only
+// SubtractAffinity(defaultAffinity, pod.Spec.Affinity) rather than reimplementing
+// this ad hoc.
+func SubtractAffinity(a, b *api.Affinity) *api.Affinity {
+	if a == nil {
+		return nil
+	}
+	var bNodeAffinity *api.NodeAffinity
+	var bPodAffinity *api.PodAffinity
+	var bPodAntiAffinity *api.PodAntiAffinity
+	if b != nil {
+		bNodeAffinity, bPodAffinity, bPodAntiAffinity = b.NodeAffinity, b.PodAffinity, b.PodAntiAffinity
+	}
+
+	out := a.DeepCopy()
+	out.NodeAffinity = subtractNodeAffinity(out.NodeAffinity, bNodeAffinity)
+	out.PodAffinity = subtractPodAffinity(out.PodAffinity, bPodAffinity)
+	out.PodAntiAffinity = subtractPodAntiAffinity(out.PodAntiAffinity, bPodAntiAffinity)
+	return out
+}
+
+func subtractNodeAffinity(a, b *api.NodeAffinity) *api.NodeAffinity {
+	if a == nil {
+		return nil
+	}
+	out := a.DeepCopy()
+
+	if out.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		bTerms := bReqTerms(requiredNodeSelector(b))
+		var kept []api.NodeSelectorTerm
+		for _, t := range out.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			implied := false
+			for _, bt := range bTerms {
+				if nodeSelectorTermImplies(bt, t) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, t)
+			}
+		}
+		out.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = kept
+	}
+
+	if len(out.PreferredDuringSchedulingIgnoredDuringExecution) > 0 && b != nil {
+		var kept []api.PreferredSchedulingTerm
+		for _, p := range out.PreferredDuringSchedulingIgnoredDuringExecution {
+			implied := false
+			for _, bp := range b.PreferredDuringSchedulingIgnoredDuringExecution {
+				if nodeSelectorTermImplies(bp.Preference, p.Preference) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, p)
+			}
+		}
+		out.PreferredDuringSchedulingIgnoredDuringExecution = kept
+	}
+	return out
+}
+
+func bReqTerms(ns *api.NodeSelector) []api.NodeSelectorTerm {
+	if ns == nil {
+		return nil
+	}
+	return ns.NodeSelectorTerms
+}
+
+func subtractPodAffinity(a, b *api.PodAffinity) *api.PodAffinity {
+	if a == nil {
+		return nil
+	}
+	out := a.DeepCopy()
+	bTerms := requiredPodAffinityTerms(b)
+
+	if len(out.RequiredDuringSchedulingIgnoredDuringExecution) > 0 {
+		var kept []api.PodAffinityTerm
+		for _, t := range out.RequiredDuringSchedulingIgnoredDuringExecution {
+			implied := false
+			for _, bt := range bTerms {
+				if podAffinityTermImplies(bt, t) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, t)
+			}
+		}
+		out.RequiredDuringSchedulingIgnoredDuringExecution = kept
+	}
+
+	if len(out.PreferredDuringSchedulingIgnoredDuringExecution) > 0 && b != nil {
+		var kept []api.WeightedPodAffinityTerm
+		for _, p := range out.PreferredDuringSchedulingIgnoredDuringExecution {
+			implied := false
+			for _, bp := range b.PreferredDuringSchedulingIgnoredDuringExecution {
+				if podAffinityTermImplies(bp.PodAffinityTerm, p.PodAffinityTerm) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, p)
+			}
+		}
+		out.PreferredDuringSchedulingIgnoredDuringExecution = kept
+	}
+	return out
+}
+
+func subtractPodAntiAffinity(a, b *api.PodAntiAffinity) *api.PodAntiAffinity {
+	if a == nil {
+		return nil
+	}
+	out := a.DeepCopy()
+	bTerms := requiredPodAntiAffinityTerms(b)
+
+	if len(out.RequiredDuringSchedulingIgnoredDuringExecution) > 0 {
+		var kept []api.PodAffinityTerm
+		for _, t := range out.RequiredDuringSchedulingIgnoredDuringExecution {
+			implied := false
+			for _, bt := range bTerms {
+				if antiAffinityTermDominates(bt, t) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, t)
+			}
+		}
+		out.RequiredDuringSchedulingIgnoredDuringExecution = kept
+	}
+
+	if len(out.PreferredDuringSchedulingIgnoredDuringExecution) > 0 && b != nil {
+		var kept []api.WeightedPodAffinityTerm
+		for _, p := range out.PreferredDuringSchedulingIgnoredDuringExecution {
+			implied := false
+			for _, bp := range b.PreferredDuringSchedulingIgnoredDuringExecution {
+				if antiAffinityTermDominates(bp.PodAffinityTerm, p.PodAffinityTerm) {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				kept = append(kept, p)
+			}
+		}
+		out.PreferredDuringSchedulingIgnoredDuringExecution = kept
+	}
+	return out
+}
+
+// PreferredWeightMerge merges two lists of weighted pod affinity terms, summing
+// the weights (capped at 100) when the same PodAffinityTerm appears on both
+// sides instead of producing duplicate preferred entries.
+func PreferredWeightMerge(a, b []api.WeightedPodAffinityTerm) []api.WeightedPodAffinityTerm {
+	return sumWeightedPodAffinityTerms(a, b)
+}
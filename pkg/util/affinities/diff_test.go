@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"testing"
+
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestContainsAffinity(t *testing.T) {
+	outer := nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1", "us-west-1"))
+	inner := nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1"))
+
+	if !ContainsAffinity(&outer, &inner) {
+		t.Errorf("expected outer to already contain the narrower inner affinity")
+	}
+	if ContainsAffinity(&inner, &outer) {
+		t.Errorf("expected the narrower inner affinity not to contain the broader outer one")
+	}
+}
+
+func TestSubtractAffinityDropsImpliedRequiredTerms(t *testing.T) {
+	a := nodeAffinityOf(
+		nodeReq("zone", api.NodeSelectorOpIn, "us-east-1"),
+	)
+	b := nodeAffinityOf(
+		nodeReq("zone", api.NodeSelectorOpIn, "us-east-1", "us-west-1"),
+	)
+
+	got := SubtractAffinity(&a, &b)
+	if got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms != nil {
+		t.Errorf("expected the term implied by b to be removed, got %+v", got.NodeAffinity)
+	}
+}
+
+func TestSubtractAffinityKeepsUnrelatedTerms(t *testing.T) {
+	a := nodeAffinityOf(
+		nodeReq("disk", api.NodeSelectorOpIn, "ssd"),
+	)
+	b := nodeAffinityOf(
+		nodeReq("zone", api.NodeSelectorOpIn, "us-east-1"),
+	)
+
+	got := SubtractAffinity(&a, &b)
+	if len(got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) != 1 {
+		t.Errorf("expected the unrelated term to be kept, got %+v", got.NodeAffinity)
+	}
+}
+
+func TestPreferredWeightMerge(t *testing.T) {
+	term := api.PodAffinityTerm{TopologyKey: "zone"}
+	a := []api.WeightedPodAffinityTerm{{Weight: 60, PodAffinityTerm: term}}
+	b := []api.WeightedPodAffinityTerm{{Weight: 70, PodAffinityTerm: term}}
+
+	merged := PreferredWeightMerge(a, b)
+	if len(merged) != 1 {
+		t.Fatalf("expected duplicate terms to merge into one entry, got %d", len(merged))
+	}
+	if merged[0].Weight != 100 {
+		t.Errorf("expected summed weight to be capped at 100, got %d", merged[0].Weight)
+	}
+}
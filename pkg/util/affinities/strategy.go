@@ -0,0 +1,384 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"sort"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// MergeStrategy controls how MergePodAffinitiesWithStrategy combines a pod's
+// affinity with a default affinity.
+type MergeStrategy string
+
+const (
+	// StrategyAppend appends the default's terms onto the pod's terms. This is the
+	// historical behavior of MergePodAffinities: required NodeSelectorTerms are OR'd
+	// together (more NodeSelectorTerms), required PodAffinity and PodAntiAffinity
+	// terms are concatenated (and so AND'd), and preferred terms are concatenated
+	// as-is.
+	StrategyAppend MergeStrategy = "Append"
+
+	// StrategyOverrideIfSet ignores a default sub-field (NodeAffinity, PodAffinity,
+	// or PodAntiAffinity) entirely when the pod already sets the corresponding
+	// sub-field, instead of merging the two.
+	StrategyOverrideIfSet MergeStrategy = "OverrideIfSet"
+
+	// StrategyIntersect ANDs the pod's and default's required terms together. For
+	// NodeAffinity this takes the cross-product of NodeSelectorTerms, concatenating
+	// MatchExpressions/MatchFields within each combined term. For PodAffinity and
+	// PodAntiAffinity, whose required terms are already AND'd, this concatenates
+	// the term lists. Preferred terms are still appended, with weights summed when
+	// the same term appears on both sides.
+	StrategyIntersect MergeStrategy = "Intersect"
+
+	// StrategyDedup appends terms like StrategyAppend, but drops terms that are
+	// semantically equal to one another after canonical sorting. Canonical sorting
+	// applies to NodeSelectorTerms and node preferences; PodAffinity and
+	// PodAntiAffinity terms are compared as they are, so only exact duplicates of
+	// those are dropped.
+	StrategyDedup MergeStrategy = "Dedup"
+)
+
+// MergePodAffinitiesWithStrategy merges a pod's affinity with a default affinity
+// using the given MergeStrategy. The strategy is applied independently to
+// NodeAffinity, PodAffinity, and PodAntiAffinity.
+func MergePodAffinitiesWithStrategy(podAffinity, defaultAffinity *api.Affinity, strategy MergeStrategy) *api.Affinity {
+	if defaultAffinity == nil {
+		return podAffinity.DeepCopy()
+	}
+	if podAffinity == nil {
+		return defaultAffinity.DeepCopy()
+	}
+
+	merged := podAffinity.DeepCopy()
+	merged.NodeAffinity = mergeNodeAffinity(podAffinity.NodeAffinity, defaultAffinity.NodeAffinity, strategy)
+	merged.PodAffinity = mergePodAffinityField(podAffinity.PodAffinity, defaultAffinity.PodAffinity, strategy)
+	merged.PodAntiAffinity = mergePodAntiAffinityField(podAffinity.PodAntiAffinity, defaultAffinity.PodAntiAffinity, strategy)
+	return merged
+}
+
+func mergeNodeAffinity(pod, def *api.NodeAffinity, strategy MergeStrategy) *api.NodeAffinity {
+	if def == nil {
+		return pod.DeepCopy()
+	}
+	if pod == nil {
+		return def.DeepCopy()
+	}
+	if strategy == StrategyOverrideIfSet {
+		return pod.DeepCopy()
+	}
+
+	merged := pod.DeepCopy()
+	merged.RequiredDuringSchedulingIgnoredDuringExecution = mergeNodeSelector(
+		pod.RequiredDuringSchedulingIgnoredDuringExecution,
+		def.RequiredDuringSchedulingIgnoredDuringExecution,
+		strategy,
+	)
+	if strategy == StrategyIntersect {
+		merged.PreferredDuringSchedulingIgnoredDuringExecution = sumWeightedNodePreferences(
+			pod.PreferredDuringSchedulingIgnoredDuringExecution,
+			def.PreferredDuringSchedulingIgnoredDuringExecution,
+		)
+	} else {
+		merged.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			append([]api.PreferredSchedulingTerm{}, pod.PreferredDuringSchedulingIgnoredDuringExecution...),
+			def.PreferredDuringSchedulingIgnoredDuringExecution...)
+		if strategy == StrategyDedup {
+			merged.PreferredDuringSchedulingIgnoredDuringExecution = dedupPreferredSchedulingTerms(merged.PreferredDuringSchedulingIgnoredDuringExecution)
+		}
+	}
+	return merged
+}
+
+func mergeNodeSelector(pod, def *api.NodeSelector, strategy MergeStrategy) *api.NodeSelector {
+	if def == nil {
+		return pod.DeepCopy()
+	}
+	if pod == nil {
+		return def.DeepCopy()
+	}
+
+	switch strategy {
+	case StrategyIntersect:
+		return &api.NodeSelector{NodeSelectorTerms: intersectNodeSelectorTerms(pod.NodeSelectorTerms, def.NodeSelectorTerms)}
+	case StrategyDedup:
+		terms := append(append([]api.NodeSelectorTerm{}, pod.NodeSelectorTerms...), def.NodeSelectorTerms...)
+		return &api.NodeSelector{NodeSelectorTerms: dedupNodeSelectorTerms(terms)}
+	default:
+		terms := append(append([]api.NodeSelectorTerm{}, pod.NodeSelectorTerms...), def.NodeSelectorTerms...)
+		return &api.NodeSelector{NodeSelectorTerms: terms}
+	}
+}
+
+// intersectNodeSelectorTerms ANDs podTerms and defTerms together by taking their
+// cross-product: since NodeSelectorTerms are OR'd but the requirements within a
+// term are AND'd, AND-ing two OR-of-AND expressions means combining every pair of
+// terms, one from each side, into a single term holding both sides' requirements.
+func intersectNodeSelectorTerms(podTerms, defTerms []api.NodeSelectorTerm) []api.NodeSelectorTerm {
+	if len(podTerms) == 0 {
+		return append([]api.NodeSelectorTerm{}, defTerms...)
+	}
+	if len(defTerms) == 0 {
+		return append([]api.NodeSelectorTerm{}, podTerms...)
+	}
+
+	combined := make([]api.NodeSelectorTerm, 0, len(podTerms)*len(defTerms))
+	for _, p := range podTerms {
+		for _, d := range defTerms {
+			combined = append(combined, api.NodeSelectorTerm{
+				MatchExpressions: append(append([]api.NodeSelectorRequirement{}, p.MatchExpressions...), d.MatchExpressions...),
+				MatchFields:      append(append([]api.NodeSelectorRequirement{}, p.MatchFields...), d.MatchFields...),
+			})
+		}
+	}
+	return combined
+}
+
+// canonicalNodeSelectorTerm returns a copy of t with its requirement lists sorted
+// by key, then operator, then sorted values, so semantically equal terms compare
+// equal regardless of original ordering.
+func canonicalNodeSelectorTerm(t api.NodeSelectorTerm) api.NodeSelectorTerm {
+	return api.NodeSelectorTerm{
+		MatchExpressions: canonicalNodeSelectorRequirements(t.MatchExpressions),
+		MatchFields:      canonicalNodeSelectorRequirements(t.MatchFields),
+	}
+}
+
+func canonicalNodeSelectorRequirements(reqs []api.NodeSelectorRequirement) []api.NodeSelectorRequirement {
+	if reqs == nil {
+		return nil
+	}
+	out := make([]api.NodeSelectorRequirement, len(reqs))
+	for i, r := range reqs {
+		values := append([]string{}, r.Values...)
+		sort.Strings(values)
+		out[i] = api.NodeSelectorRequirement{Key: r.Key, Operator: r.Operator, Values: values}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		if out[i].Operator != out[j].Operator {
+			return out[i].Operator < out[j].Operator
+		}
+		return stringSliceLess(out[i].Values, out[j].Values)
+	})
+	return out
+}
+
+// stringSliceLess provides a total order over string slices so that two
+// requirements with the same key and operator sort deterministically.
+func stringSliceLess(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func dedupNodeSelectorTerms(terms []api.NodeSelectorTerm) []api.NodeSelectorTerm {
+	var deduped []api.NodeSelectorTerm
+	for _, t := range terms {
+		canon := canonicalNodeSelectorTerm(t)
+		duplicate := false
+		for _, existing := range deduped {
+			if apiequality.Semantic.DeepEqual(canonicalNodeSelectorTerm(existing), canon) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			deduped = append(deduped, t)
+		}
+	}
+	return deduped
+}
+
+func sumWeightedNodePreferences(pod, def []api.PreferredSchedulingTerm) []api.PreferredSchedulingTerm {
+	merged := append([]api.PreferredSchedulingTerm{}, pod...)
+	for _, d := range def {
+		found := false
+		for i, m := range merged {
+			if apiequality.Semantic.DeepEqual(m.Preference, d.Preference) {
+				merged[i].Weight = capWeight(m.Weight + d.Weight)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
+
+func dedupPreferredSchedulingTerms(terms []api.PreferredSchedulingTerm) []api.PreferredSchedulingTerm {
+	var deduped []api.PreferredSchedulingTerm
+	for _, t := range terms {
+		duplicate := false
+		for _, existing := range deduped {
+			if existing.Weight == t.Weight &&
+				apiequality.Semantic.DeepEqual(canonicalNodeSelectorTerm(existing.Preference), canonicalNodeSelectorTerm(t.Preference)) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			deduped = append(deduped, t)
+		}
+	}
+	return deduped
+}
+
+func capWeight(w int32) int32 {
+	if w > 100 {
+		return 100
+	}
+	return w
+}
+
+// mergeRequiredAndPreferredTerms merges a pair of required/preferred PodAffinityTerm
+// lists according to strategy. It holds the logic shared by mergePodAffinityField and
+// mergePodAntiAffinityField, which differ only in which struct type they unpack it into.
+func mergeRequiredAndPreferredTerms(
+	podRequired, defRequired []api.PodAffinityTerm,
+	podPreferred, defPreferred []api.WeightedPodAffinityTerm,
+	strategy MergeStrategy,
+) ([]api.PodAffinityTerm, []api.WeightedPodAffinityTerm) {
+	required := mergePodAffinityTerms(podRequired, defRequired, strategy)
+
+	var preferred []api.WeightedPodAffinityTerm
+	if strategy == StrategyIntersect {
+		preferred = sumWeightedPodAffinityTerms(podPreferred, defPreferred)
+	} else {
+		preferred = append(append([]api.WeightedPodAffinityTerm{}, podPreferred...), defPreferred...)
+		if strategy == StrategyDedup {
+			preferred = dedupWeightedPodAffinityTerms(preferred)
+		}
+	}
+	return required, preferred
+}
+
+func mergePodAffinityField(pod, def *api.PodAffinity, strategy MergeStrategy) *api.PodAffinity {
+	if def == nil {
+		return pod.DeepCopy()
+	}
+	if pod == nil {
+		return def.DeepCopy()
+	}
+	if strategy == StrategyOverrideIfSet {
+		return pod.DeepCopy()
+	}
+
+	merged := pod.DeepCopy()
+	merged.RequiredDuringSchedulingIgnoredDuringExecution, merged.PreferredDuringSchedulingIgnoredDuringExecution =
+		mergeRequiredAndPreferredTerms(
+			pod.RequiredDuringSchedulingIgnoredDuringExecution, def.RequiredDuringSchedulingIgnoredDuringExecution,
+			pod.PreferredDuringSchedulingIgnoredDuringExecution, def.PreferredDuringSchedulingIgnoredDuringExecution,
+			strategy,
+		)
+	return merged
+}
+
+func mergePodAntiAffinityField(pod, def *api.PodAntiAffinity, strategy MergeStrategy) *api.PodAntiAffinity {
+	if def == nil {
+		return pod.DeepCopy()
+	}
+	if pod == nil {
+		return def.DeepCopy()
+	}
+	if strategy == StrategyOverrideIfSet {
+		return pod.DeepCopy()
+	}
+
+	merged := pod.DeepCopy()
+	merged.RequiredDuringSchedulingIgnoredDuringExecution, merged.PreferredDuringSchedulingIgnoredDuringExecution =
+		mergeRequiredAndPreferredTerms(
+			pod.RequiredDuringSchedulingIgnoredDuringExecution, def.RequiredDuringSchedulingIgnoredDuringExecution,
+			pod.PreferredDuringSchedulingIgnoredDuringExecution, def.PreferredDuringSchedulingIgnoredDuringExecution,
+			strategy,
+		)
+	return merged
+}
+
+// mergePodAffinityTerms combines pod's and def's required PodAffinityTerm lists.
+// Required terms are already AND'd together, so for both StrategyAppend and
+// StrategyIntersect the lists are simply concatenated; StrategyDedup additionally
+// drops exact duplicates.
+func mergePodAffinityTerms(pod, def []api.PodAffinityTerm, strategy MergeStrategy) []api.PodAffinityTerm {
+	terms := append(append([]api.PodAffinityTerm{}, pod...), def...)
+	if strategy == StrategyDedup {
+		return dedupPodAffinityTerms(terms)
+	}
+	return terms
+}
+
+func dedupPodAffinityTerms(terms []api.PodAffinityTerm) []api.PodAffinityTerm {
+	var deduped []api.PodAffinityTerm
+	for _, t := range terms {
+		duplicate := false
+		for _, existing := range deduped {
+			if apiequality.Semantic.DeepEqual(existing, t) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			deduped = append(deduped, t)
+		}
+	}
+	return deduped
+}
+
+func sumWeightedPodAffinityTerms(pod, def []api.WeightedPodAffinityTerm) []api.WeightedPodAffinityTerm {
+	merged := append([]api.WeightedPodAffinityTerm{}, pod...)
+	for _, d := range def {
+		found := false
+		for i, m := range merged {
+			if apiequality.Semantic.DeepEqual(m.PodAffinityTerm, d.PodAffinityTerm) {
+				merged[i].Weight = capWeight(m.Weight + d.Weight)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, d)
+		}
+	}
+	return merged
+}
+
+func dedupWeightedPodAffinityTerms(terms []api.WeightedPodAffinityTerm) []api.WeightedPodAffinityTerm {
+	var deduped []api.WeightedPodAffinityTerm
+	for _, t := range terms {
+		duplicate := false
+		for _, existing := range deduped {
+			if apiequality.Semantic.DeepEqual(existing, t) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			deduped = append(deduped, t)
+		}
+	}
+	return deduped
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"testing"
+
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestMergePodAffinitiesWithStrategyAppendVsIntersect(t *testing.T) {
+	pod := nodeAffinityOf(nodeReq("zone", api.NodeSelectorOpIn, "us-east-1"))
+	def := nodeAffinityOf(nodeReq("disk", api.NodeSelectorOpIn, "ssd"))
+
+	appended := MergePodAffinitiesWithStrategy(&pod, &def, StrategyAppend)
+	gotTerms := appended.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(gotTerms) != 2 {
+		t.Fatalf("StrategyAppend: expected 2 OR'd terms, got %d: %+v", len(gotTerms), gotTerms)
+	}
+	// Under Append, a node matching only "zone=us-east-1" still satisfies the
+	// affinity, because the two single-requirement terms are OR'd together.
+	if !nodeSelectorTermImplies(gotTerms[0], api.NodeSelectorTerm{MatchExpressions: []api.NodeSelectorRequirement{
+		{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+	}}) && !nodeSelectorTermImplies(gotTerms[1], api.NodeSelectorTerm{MatchExpressions: []api.NodeSelectorRequirement{
+		{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+	}}) {
+		t.Fatalf("StrategyAppend: expected an OR'd term satisfied by zone alone")
+	}
+
+	intersected := MergePodAffinitiesWithStrategy(&pod, &def, StrategyIntersect)
+	intersectedTerms := intersected.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(intersectedTerms) != 1 {
+		t.Fatalf("StrategyIntersect: expected a single AND'd term, got %d: %+v", len(intersectedTerms), intersectedTerms)
+	}
+	// Under Intersect, a node matching only "zone=us-east-1" no longer satisfies
+	// the affinity: "disk=ssd" must also hold within the same term.
+	onlyZone := api.NodeSelectorTerm{MatchExpressions: []api.NodeSelectorRequirement{
+		{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+	}}
+	if nodeSelectorTermImplies(intersectedTerms[0], onlyZone) {
+		t.Fatalf("StrategyIntersect: a node matching zone alone should no longer satisfy the merged term")
+	}
+	if len(intersectedTerms[0].MatchExpressions) != 2 {
+		t.Fatalf("StrategyIntersect: expected both requirements AND'd into one term, got %+v", intersectedTerms[0])
+	}
+}
+
+func TestMergePodAffinitiesWithStrategyOverrideIfSet(t *testing.T) {
+	pod := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}}}},
+				},
+			},
+		},
+	}
+	def := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+				NodeSelectorTerms: []api.NodeSelectorTerm{
+					{MatchExpressions: []api.NodeSelectorRequirement{{Key: "disk", Operator: api.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+				},
+			},
+		},
+	}
+
+	merged := MergePodAffinitiesWithStrategy(pod, def, StrategyOverrideIfSet)
+	if len(merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) != 1 {
+		t.Fatalf("StrategyOverrideIfSet: default's NodeAffinity should be ignored entirely, got %+v", merged.NodeAffinity)
+	}
+}
+
+func TestMergePodAffinitiesWithStrategyDedup(t *testing.T) {
+	term := api.NodeSelectorTerm{MatchExpressions: []api.NodeSelectorRequirement{
+		{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+	}}
+	pod := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{term}},
+		},
+	}
+	def := &api.Affinity{
+		NodeAffinity: &api.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{term}},
+		},
+	}
+
+	merged := MergePodAffinitiesWithStrategy(pod, def, StrategyDedup)
+	if got := len(merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms); got != 1 {
+		t.Fatalf("StrategyDedup: expected the duplicate term to be dropped, got %d terms", got)
+	}
+}
+
+// TestMergePodAffinitiesWithStrategyNilShortCircuitsDoNotAlias guards against a
+// shared template *api.Affinity (e.g. the anchor a Transformer closes over) being
+// handed back verbatim and then mutated through one caller's copy of the result,
+// corrupting every other pod merged against the same template.
+func TestMergePodAffinitiesWithStrategyNilShortCircuitsDoNotAlias(t *testing.T) {
+	template := &api.Affinity{
+		PodAffinity: &api.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+				{TopologyKey: "kubernetes.io/hostname"},
+			},
+		},
+	}
+
+	podA := MergePodAffinitiesWithStrategy(nil, template, StrategyAppend)
+	podB := MergePodAffinitiesWithStrategy(nil, template, StrategyAppend)
+
+	if podA == template || podB == template {
+		t.Fatalf("MergePodAffinitiesWithStrategy returned the template pointer itself, not a copy")
+	}
+	if podA.PodAffinity == podB.PodAffinity {
+		t.Fatalf("two merges against the same default share the same PodAffinity pointer")
+	}
+
+	podA.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+		podA.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		api.PodAffinityTerm{TopologyKey: "topology.kubernetes.io/zone"},
+	)
+	if got := len(podB.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution); got != 1 {
+		t.Fatalf("mutating podA's merged affinity leaked into podB: got %d required terms, want 1", got)
+	}
+	if got := len(template.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution); got != 1 {
+		t.Fatalf("mutating podA's merged affinity leaked into the shared template: got %d required terms, want 1", got)
+	}
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"strconv"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// isNodeAffinitySuperset checks if ss is a superset of a, i.e. whether every node
+// matched by a's required node selector is also matched by ss's. Only the
+// RequiredDuringSchedulingIgnoredDuringExecution selector participates; preferred
+// terms are non-blocking and are ignored for superset comparisons.
+func isNodeAffinitySuperset(ss, a *api.NodeAffinity) bool {
+	return isNodeSelectorSuperset(requiredNodeSelector(ss), requiredNodeSelector(a))
+}
+
+func requiredNodeSelector(na *api.NodeAffinity) *api.NodeSelector {
+	if na == nil {
+		return nil
+	}
+	return na.RequiredDuringSchedulingIgnoredDuringExecution
+}
+
+// isNodeSelectorSuperset checks whether every term in a is implied by some term in
+// ss. NodeSelectorTerms are OR'd, so ss matches a superset of nodes when each of a's
+// (narrower) alternatives is covered by one of ss's.
+func isNodeSelectorSuperset(ss, a *api.NodeSelector) bool {
+	if ss == nil {
+		// No constraint on the ss side is always a superset.
+		return true
+	}
+	if a == nil {
+		return false
+	}
+
+	for _, aTerm := range a.NodeSelectorTerms {
+		covered := false
+		for _, ssTerm := range ss.NodeSelectorTerms {
+			if nodeSelectorTermImplies(ssTerm, aTerm) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSelectorTermImplies reports whether every requirement in ssTerm is covered by
+// a requirement on the same key in aTerm, i.e. whether ssTerm is less restrictive
+// than (or as restrictive as) aTerm.
+func nodeSelectorTermImplies(ss, a api.NodeSelectorTerm) bool {
+	return nodeSelectorRequirementsImplied(ss.MatchExpressions, a.MatchExpressions) &&
+		nodeSelectorRequirementsImplied(ss.MatchFields, a.MatchFields)
+}
+
+func nodeSelectorRequirementsImplied(ssReqs, aReqs []api.NodeSelectorRequirement) bool {
+	for _, ssReq := range ssReqs {
+		if !nodeSelectorRequirementImplied(ssReq, aReqs) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSelectorRequirementImplied reports whether ssReq is covered by some
+// requirement on the same key in aReqs.
+func nodeSelectorRequirementImplied(ssReq api.NodeSelectorRequirement, aReqs []api.NodeSelectorRequirement) bool {
+	switch ssReq.Operator {
+	case api.NodeSelectorOpIn:
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && aReq.Operator == api.NodeSelectorOpIn &&
+				sets.NewString(ssReq.Values...).IsSuperset(sets.NewString(aReq.Values...)) {
+				return true
+			}
+		}
+		return false
+	case api.NodeSelectorOpNotIn:
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && aReq.Operator == api.NodeSelectorOpNotIn &&
+				sets.NewString(aReq.Values...).IsSuperset(sets.NewString(ssReq.Values...)) {
+				return true
+			}
+		}
+		return false
+	case api.NodeSelectorOpExists:
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && (aReq.Operator == api.NodeSelectorOpIn || aReq.Operator == api.NodeSelectorOpExists) {
+				return true
+			}
+		}
+		return false
+	case api.NodeSelectorOpDoesNotExist:
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && aReq.Operator == api.NodeSelectorOpDoesNotExist {
+				return true
+			}
+		}
+		return false
+	case api.NodeSelectorOpGt:
+		ssVal, ok := soleInt(ssReq.Values)
+		if !ok {
+			return false
+		}
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && aReq.Operator == api.NodeSelectorOpGt {
+				if aVal, ok := soleInt(aReq.Values); ok && ssVal <= aVal {
+					return true
+				}
+			}
+		}
+		return false
+	case api.NodeSelectorOpLt:
+		ssVal, ok := soleInt(ssReq.Values)
+		if !ok {
+			return false
+		}
+		for _, aReq := range aReqs {
+			if aReq.Key == ssReq.Key && aReq.Operator == api.NodeSelectorOpLt {
+				if aVal, ok := soleInt(aReq.Values); ok && ssVal >= aVal {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func soleInt(values []string) (int64, bool) {
+	if len(values) != 1 {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// isPodAffinitySuperset checks if ss is a superset of a. Only the
+// RequiredDuringSchedulingIgnoredDuringExecution terms participate; preferred
+// terms are non-blocking and are ignored for superset comparisons.
+func isPodAffinitySuperset(ss, a *api.PodAffinity) bool {
+	return podAffinityTermsImplied(requiredPodAffinityTerms(ss), requiredPodAffinityTerms(a))
+}
+
+func requiredPodAffinityTerms(pa *api.PodAffinity) []api.PodAffinityTerm {
+	if pa == nil {
+		return nil
+	}
+	return pa.RequiredDuringSchedulingIgnoredDuringExecution
+}
+
+func requiredPodAntiAffinityTerms(pa *api.PodAntiAffinity) []api.PodAffinityTerm {
+	if pa == nil {
+		return nil
+	}
+	return pa.RequiredDuringSchedulingIgnoredDuringExecution
+}
+
+// podAffinityTermsImplied reports whether ssTerms is a superset of aTerms: since
+// terms in a PodAffinity are AND'd, ss is less restrictive than a iff every term in
+// ss has a counterpart in a that it is implied by.
+func podAffinityTermsImplied(ssTerms, aTerms []api.PodAffinityTerm) bool {
+	if len(ssTerms) == 0 {
+		return true
+	}
+	if len(aTerms) == 0 {
+		return false
+	}
+	for _, ssTerm := range ssTerms {
+		covered := false
+		for _, aTerm := range aTerms {
+			if podAffinityTermImplies(ssTerm, aTerm) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// podAffinityTermImplies reports whether ssTerm is implied by aTerm: they must
+// share a topology key, ssTerm's label selector must match a superset of pods
+// (i.e. be a subset of aTerm's constraints), and ssTerm's namespace scope must
+// cover aTerm's.
+func podAffinityTermImplies(ssTerm, aTerm api.PodAffinityTerm) bool {
+	if ssTerm.TopologyKey != aTerm.TopologyKey {
+		return false
+	}
+	if !labelSelectorIsSubset(ssTerm.LabelSelector, aTerm.LabelSelector) {
+		return false
+	}
+	return namespaceScopeCovers(ssTerm, aTerm)
+}
+
+// labelSelectorIsSubset reports whether every requirement in ss also appears in a,
+// i.e. ss has fewer (or equally many) constraints and therefore matches at least as
+// many objects as a. A nil selector matches everything and has no requirements.
+//
+// Requirements are compared literally (same key, operator, and value set), so this
+// does not recognize a value-set superset like "env In (prod,staging)" as broader than
+// "env In (prod)". That only means some redundant terms won't be deduped; it never
+// causes two non-equivalent terms to be merged incorrectly.
+func labelSelectorIsSubset(ss, a *metav1.LabelSelector) bool {
+	ssReqs := labelSelectorRequirements(ss)
+	if len(ssReqs) == 0 {
+		return true
+	}
+	aReqs := labelSelectorRequirements(a)
+	for _, ssReq := range ssReqs {
+		found := false
+		for _, aReq := range aReqs {
+			if labelSelectorRequirementsEqual(ssReq, aReq) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// labelSelectorRequirements normalizes a LabelSelector's MatchLabels into
+// equivalent In requirements and appends its MatchExpressions, so the two
+// representations can be compared uniformly.
+func labelSelectorRequirements(sel *metav1.LabelSelector) []metav1.LabelSelectorRequirement {
+	if sel == nil {
+		return nil
+	}
+	reqs := make([]metav1.LabelSelectorRequirement, 0, len(sel.MatchLabels)+len(sel.MatchExpressions))
+	for k, v := range sel.MatchLabels {
+		reqs = append(reqs, metav1.LabelSelectorRequirement{Key: k, Operator: metav1.LabelSelectorOpIn, Values: []string{v}})
+	}
+	reqs = append(reqs, sel.MatchExpressions...)
+	return reqs
+}
+
+func labelSelectorRequirementsEqual(x, y metav1.LabelSelectorRequirement) bool {
+	if x.Key != y.Key || x.Operator != y.Operator {
+		return false
+	}
+	return sets.NewString(x.Values...).Equal(sets.NewString(y.Values...))
+}
+
+// namespaceScopeCovers reports whether ssTerm's namespace scope covers aTerm's,
+// i.e. every namespace aTerm can match is also matched by ssTerm. Terms with
+// neither Namespaces nor NamespaceSelector set default to the pod's own namespace.
+func namespaceScopeCovers(ssTerm, aTerm api.PodAffinityTerm) bool {
+	if ssTerm.NamespaceSelector != nil {
+		if aTerm.NamespaceSelector == nil {
+			return false
+		}
+		if apiequality.Semantic.DeepEqual(ssTerm.NamespaceSelector, aTerm.NamespaceSelector) {
+			return true
+		}
+		// An empty selector matches every namespace, which trivially covers any
+		// narrower scope.
+		return len(ssTerm.NamespaceSelector.MatchLabels) == 0 && len(ssTerm.NamespaceSelector.MatchExpressions) == 0
+	}
+	if aTerm.NamespaceSelector != nil {
+		return false
+	}
+	if len(ssTerm.Namespaces) == 0 {
+		// ss scopes to the pod's own namespace, the narrowest possible scope; it
+		// only covers a if a has that same scope.
+		return len(aTerm.Namespaces) == 0
+	}
+	return sets.NewString(ssTerm.Namespaces...).IsSuperset(sets.NewString(aTerm.Namespaces...))
+}
+
+// isPodAntiAffinitySuperset checks if ss is a superset of a. For anti-affinity,
+// more/broader terms are *more* restrictive (they block scheduling next to more
+// pods), so ss is a superset when it is less restrictive: either its term list is
+// already covered by a's, or each of its terms is dominated by an equally broad or
+// broader, and so at least as restrictive, term in a.
+func isPodAntiAffinitySuperset(ss, a *api.PodAntiAffinity) bool {
+	ssTerms := requiredPodAntiAffinityTerms(ss)
+	aTerms := requiredPodAntiAffinityTerms(a)
+
+	if len(ssTerms) == 0 {
+		return true
+	}
+	if antiAffinityTermListSubset(ssTerms, aTerms) {
+		return true
+	}
+	for _, ssTerm := range ssTerms {
+		dominated := false
+		for _, aTerm := range aTerms {
+			if antiAffinityTermDominates(aTerm, ssTerm) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			return false
+		}
+	}
+	return true
+}
+
+// antiAffinityTermListSubset reports whether every term in ssTerms also appears
+// (semantically) in aTerms.
+func antiAffinityTermListSubset(ssTerms, aTerms []api.PodAffinityTerm) bool {
+	for _, ssTerm := range ssTerms {
+		found := false
+		for _, aTerm := range aTerms {
+			if apiequality.Semantic.DeepEqual(&ssTerm, &aTerm) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// antiAffinityTermDominates reports whether aTerm is at least as restrictive as
+// ssTerm: same topology key, aTerm's label selector matches a superset of pods
+// (narrower/equal constraint set than ssTerm's), and aTerm's namespace scope
+// covers ssTerm's.
+func antiAffinityTermDominates(aTerm, ssTerm api.PodAffinityTerm) bool {
+	if aTerm.TopologyKey != ssTerm.TopologyKey {
+		return false
+	}
+	if !labelSelectorIsSubset(aTerm.LabelSelector, ssTerm.LabelSelector) {
+		return false
+	}
+	return namespaceScopeCovers(aTerm, ssTerm)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// PVCColocationLabelKey is the synthetic label NewPVCColocationTransformer
+// matches on. Callers are expected to apply this label (with the PVC's name as
+// its value) to whichever pod is bound to the PVC, the same way the
+// affinity-assistant pattern labels an anchor pod so siblings can co-locate with
+// it via PodAffinity instead of a direct node reference.
+const PVCColocationLabelKey = "affinities.kubernetes.io/pvc-colocation"
+
+// Transformer mutates a pod's affinity, returning the transformed pod (as a copy)
+// or an error. Transformers compose via Chain.
+type Transformer func(pod *api.Pod) (*api.Pod, error)
+
+// NewPVCColocationTransformer returns a Transformer that adds a required
+// PodAffinity term co-locating the pod, by topologyKey, with whichever pod
+// carries the PVCColocationLabelKey=pvcName label. It merges this term into the
+// pod's existing affinity using strategy, so it never clobbers user-supplied
+// affinity outright.
+func NewPVCColocationTransformer(pvcName, topologyKey string, strategy MergeStrategy) Transformer {
+	term := api.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{PVCColocationLabelKey: pvcName}},
+		TopologyKey:   topologyKey,
+	}
+	return newPodAffinityTransformer(term, strategy)
+}
+
+// NewCoScheduleTransformer returns a Transformer that adds a required
+// PodAffinity term co-locating the pod, by topologyKey, with any pod matched by
+// labelSelector. It merges this term into the pod's existing affinity using
+// strategy.
+func NewCoScheduleTransformer(labelSelector *metav1.LabelSelector, topologyKey string, strategy MergeStrategy) Transformer {
+	term := api.PodAffinityTerm{
+		LabelSelector: labelSelector,
+		TopologyKey:   topologyKey,
+	}
+	return newPodAffinityTransformer(term, strategy)
+}
+
+func newPodAffinityTransformer(term api.PodAffinityTerm, strategy MergeStrategy) Transformer {
+	anchor := &api.Affinity{
+		PodAffinity: &api.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{term},
+		},
+	}
+	return func(pod *api.Pod) (*api.Pod, error) {
+		if pod == nil {
+			return nil, fmt.Errorf("affinities: cannot transform a nil pod")
+		}
+		out := pod.DeepCopy()
+		out.Spec.Affinity = MergePodAffinitiesWithStrategy(out.Spec.Affinity, anchor, strategy)
+		return out, nil
+	}
+}
+
+// Chain composes transformers, applying them in order and validating the final
+// result with ValidateAffinity.
+func Chain(transformers ...Transformer) Transformer {
+	return func(pod *api.Pod) (*api.Pod, error) {
+		current := pod
+		for _, t := range transformers {
+			next, err := t(current)
+			if err != nil {
+				return nil, err
+			}
+			current = next
+		}
+		if current != nil {
+			if errs := ValidateAffinity(current.Spec.Affinity); len(errs) > 0 {
+				return nil, errs.ToAggregate()
+			}
+		}
+		return current, nil
+	}
+}
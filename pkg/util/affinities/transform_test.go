@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+var errTransformerFailed = errors.New("transformer failed")
+
+func TestNewPVCColocationTransformerPreservesUserAffinity(t *testing.T) {
+	pod := &api.Pod{
+		Spec: api.PodSpec{
+			Affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	transform := NewPVCColocationTransformer("data-pvc", "kubernetes.io/hostname", StrategyAppend)
+	out, err := transform(pod)
+	if err != nil {
+		t.Fatalf("transform() returned error: %v", err)
+	}
+
+	if out.Spec.Affinity.NodeAffinity == nil {
+		t.Fatalf("expected the pod's original NodeAffinity to be preserved")
+	}
+	if out.Spec.Affinity.PodAffinity == nil || len(out.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Fatalf("expected a PVC co-location PodAffinity term to be added, got %+v", out.Spec.Affinity.PodAffinity)
+	}
+	term := out.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]
+	if term.TopologyKey != "kubernetes.io/hostname" {
+		t.Errorf("expected topology key kubernetes.io/hostname, got %q", term.TopologyKey)
+	}
+	if term.LabelSelector.MatchLabels[PVCColocationLabelKey] != "data-pvc" {
+		t.Errorf("expected label selector to match PVC name, got %+v", term.LabelSelector)
+	}
+	// The original pod must not be mutated.
+	if pod.Spec.Affinity.PodAffinity != nil {
+		t.Errorf("transform must not mutate its input pod")
+	}
+}
+
+func TestChainAppliesInOrderAndValidates(t *testing.T) {
+	pod := &api.Pod{}
+
+	chained := Chain(
+		NewCoScheduleTransformer(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}, "topology.kubernetes.io/zone", StrategyAppend),
+		NewPVCColocationTransformer("shared-pvc", "kubernetes.io/hostname", StrategyAppend),
+	)
+
+	out, err := chained(pod)
+	if err != nil {
+		t.Fatalf("Chain() returned error: %v", err)
+	}
+	if got := len(out.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution); got != 2 {
+		t.Fatalf("expected both transformers' terms to be present, got %d", got)
+	}
+	terms := out.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if terms[0].TopologyKey != "topology.kubernetes.io/zone" || terms[1].TopologyKey != "kubernetes.io/hostname" {
+		t.Errorf("expected the co-schedule term before the PVC term, got %+v", terms)
+	}
+}
+
+func TestChainPropagatesTransformerError(t *testing.T) {
+	failing := func(pod *api.Pod) (*api.Pod, error) {
+		return nil, errTransformerFailed
+	}
+	chained := Chain(failing)
+
+	if _, err := chained(&api.Pod{}); err != errTransformerFailed {
+		t.Fatalf("expected Chain to propagate the transformer's error, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// ValidateAffinity validates that a is well-formed and internally consistent, so
+// that callers (admission plugins, controllers synthesizing defaults) can reject
+// bad input before handing it to MergePodAffinities.
+func ValidateAffinity(a *api.Affinity) field.ErrorList {
+	var allErrs field.ErrorList
+	if a == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateNodeAffinity(a.NodeAffinity, field.NewPath("nodeAffinity"))...)
+	allErrs = append(allErrs, validatePodAffinity(a.PodAffinity, field.NewPath("podAffinity"))...)
+	allErrs = append(allErrs, validatePodAntiAffinity(a.PodAntiAffinity, field.NewPath("podAntiAffinity"))...)
+	return allErrs
+}
+
+// ValidateAffinityPatch validates new in the context of an update from old. There
+// are currently no immutable sub-fields of Affinity, so this validates new the same
+// way ValidateAffinity does; it exists so callers have a stable entry point if that
+// changes, and for parity with the old/new update-validation convention used
+// elsewhere in this API group.
+func ValidateAffinityPatch(old, new *api.Affinity) field.ErrorList {
+	return ValidateAffinity(new)
+}
+
+func validateNodeAffinity(na *api.NodeAffinity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if na == nil {
+		return allErrs
+	}
+
+	if req := na.RequiredDuringSchedulingIgnoredDuringExecution; req != nil {
+		reqPath := fldPath.Child("requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+		for i, term := range req.NodeSelectorTerms {
+			allErrs = append(allErrs, validateNodeSelectorTerm(term, reqPath.Index(i))...)
+		}
+	}
+
+	prefPath := fldPath.Child("preferredDuringSchedulingIgnoredDuringExecution")
+	for i, pref := range na.PreferredDuringSchedulingIgnoredDuringExecution {
+		p := prefPath.Index(i)
+		allErrs = append(allErrs, validateWeight(pref.Weight, p.Child("weight"))...)
+		allErrs = append(allErrs, validateNodeSelectorTerm(pref.Preference, p.Child("preference"))...)
+	}
+	return allErrs
+}
+
+func validateNodeSelectorTerm(term api.NodeSelectorTerm, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateNodeSelectorRequirements(term.MatchExpressions, fldPath.Child("matchExpressions"))...)
+	allErrs = append(allErrs, validateNodeSelectorRequirements(term.MatchFields, fldPath.Child("matchFields"))...)
+	allErrs = append(allErrs, validateNoContradictoryRequirements(term.MatchExpressions, fldPath.Child("matchExpressions"))...)
+	allErrs = append(allErrs, validateNoContradictoryRequirements(term.MatchFields, fldPath.Child("matchFields"))...)
+	return allErrs
+}
+
+func validateNodeSelectorRequirements(reqs []api.NodeSelectorRequirement, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, r := range reqs {
+		allErrs = append(allErrs, validateNodeSelectorRequirement(r, fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+func validateNodeSelectorRequirement(r api.NodeSelectorRequirement, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsQualifiedName(r.Key) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), r.Key, msg))
+	}
+
+	switch r.Operator {
+	case api.NodeSelectorOpIn, api.NodeSelectorOpNotIn:
+		if len(r.Values) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("values"), "must specify at least one value"))
+		}
+	case api.NodeSelectorOpExists, api.NodeSelectorOpDoesNotExist:
+		if len(r.Values) != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("values"), r.Values, "may not be specified when operator is Exists or DoesNotExist"))
+		}
+	case api.NodeSelectorOpGt, api.NodeSelectorOpLt:
+		if len(r.Values) != 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("values"), r.Values, "must specify exactly one value"))
+		} else if _, err := strconv.ParseInt(r.Values[0], 10, 64); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("values").Index(0), r.Values[0], "must be a valid integer"))
+		}
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("operator"), r.Operator, "not a valid node selector operator"))
+	}
+	return allErrs
+}
+
+// validateNoContradictoryRequirements rejects requirement combinations on the same
+// key within a single AND'd list that can never be satisfied: an In and a NotIn
+// with overlapping value sets, or an Exists paired with a DoesNotExist.
+func validateNoContradictoryRequirements(reqs []api.NodeSelectorRequirement, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	type byKey struct {
+		in, notIn            sets.String
+		hasIn, hasNotIn      bool
+		exists, doesNotExist bool
+	}
+	byKeys := map[string]*byKey{}
+	for _, r := range reqs {
+		k, ok := byKeys[r.Key]
+		if !ok {
+			k = &byKey{}
+			byKeys[r.Key] = k
+		}
+		switch r.Operator {
+		case api.NodeSelectorOpIn:
+			k.in, k.hasIn = sets.NewString(r.Values...), true
+		case api.NodeSelectorOpNotIn:
+			k.notIn, k.hasNotIn = sets.NewString(r.Values...), true
+		case api.NodeSelectorOpExists:
+			k.exists = true
+		case api.NodeSelectorOpDoesNotExist:
+			k.doesNotExist = true
+		}
+	}
+
+	for key, k := range byKeys {
+		if k.hasIn && k.hasNotIn && k.in.Intersection(k.notIn).Len() > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, fmt.Sprintf("In and NotIn requirements on key %q have overlapping values", key)))
+		}
+		if k.exists && k.doesNotExist {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, fmt.Sprintf("Exists and DoesNotExist requirements on key %q are contradictory", key)))
+		}
+	}
+	return allErrs
+}
+
+func validateWeight(w int32, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if w < 1 || w > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath, w, "must be in the range 1-100"))
+	}
+	return allErrs
+}
+
+func validatePodAffinity(pa *api.PodAffinity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if pa == nil {
+		return allErrs
+	}
+
+	reqPath := fldPath.Child("requiredDuringSchedulingIgnoredDuringExecution")
+	for i, term := range pa.RequiredDuringSchedulingIgnoredDuringExecution {
+		allErrs = append(allErrs, validatePodAffinityTerm(term, true, reqPath.Index(i))...)
+	}
+
+	prefPath := fldPath.Child("preferredDuringSchedulingIgnoredDuringExecution")
+	for i, wt := range pa.PreferredDuringSchedulingIgnoredDuringExecution {
+		p := prefPath.Index(i)
+		allErrs = append(allErrs, validateWeight(wt.Weight, p.Child("weight"))...)
+		allErrs = append(allErrs, validatePodAffinityTerm(wt.PodAffinityTerm, false, p.Child("podAffinityTerm"))...)
+	}
+	return allErrs
+}
+
+func validatePodAntiAffinity(pa *api.PodAntiAffinity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if pa == nil {
+		return allErrs
+	}
+
+	reqPath := fldPath.Child("requiredDuringSchedulingIgnoredDuringExecution")
+	for i, term := range pa.RequiredDuringSchedulingIgnoredDuringExecution {
+		allErrs = append(allErrs, validatePodAffinityTerm(term, true, reqPath.Index(i))...)
+	}
+
+	prefPath := fldPath.Child("preferredDuringSchedulingIgnoredDuringExecution")
+	for i, wt := range pa.PreferredDuringSchedulingIgnoredDuringExecution {
+		p := prefPath.Index(i)
+		allErrs = append(allErrs, validateWeight(wt.Weight, p.Child("weight"))...)
+		allErrs = append(allErrs, validatePodAffinityTerm(wt.PodAffinityTerm, false, p.Child("podAffinityTerm"))...)
+	}
+	return allErrs
+}
+
+// validatePodAffinityTerm validates a single PodAffinityTerm. required indicates
+// whether this term comes from a RequiredDuringScheduling list, where TopologyKey
+// must be set.
+func validatePodAffinityTerm(term api.PodAffinityTerm, required bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(term.TopologyKey) == 0 {
+		if required {
+			allErrs = append(allErrs, field.Required(fldPath.Child("topologyKey"), "must be non-empty for required terms"))
+		}
+	} else {
+		for _, msg := range validation.IsQualifiedName(term.TopologyKey) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("topologyKey"), term.TopologyKey, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateLabelSelector(term.LabelSelector, fldPath.Child("labelSelector"))...)
+	allErrs = append(allErrs, validateLabelSelector(term.NamespaceSelector, fldPath.Child("namespaceSelector"))...)
+	return allErrs
+}
+
+func validateLabelSelector(sel *metav1.LabelSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if sel == nil {
+		return allErrs
+	}
+
+	for k, v := range sel.MatchLabels {
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("matchLabels"), k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("matchLabels"), v, msg))
+		}
+	}
+
+	for i, expr := range sel.MatchExpressions {
+		p := fldPath.Child("matchExpressions").Index(i)
+		for _, msg := range validation.IsQualifiedName(expr.Key) {
+			allErrs = append(allErrs, field.Invalid(p.Child("key"), expr.Key, msg))
+		}
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn:
+			if len(expr.Values) == 0 {
+				allErrs = append(allErrs, field.Required(p.Child("values"), "must specify at least one value"))
+			}
+		case metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist:
+			if len(expr.Values) != 0 {
+				allErrs = append(allErrs, field.Invalid(p.Child("values"), expr.Values, "may not be specified when operator is Exists or DoesNotExist"))
+			}
+		default:
+			allErrs = append(allErrs, field.Invalid(p.Child("operator"), expr.Operator, "not a valid selector operator"))
+		}
+	}
+	return allErrs
+}
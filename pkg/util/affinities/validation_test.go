@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package affinities
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestValidateAffinity(t *testing.T) {
+	tests := []struct {
+		name     string
+		affinity *api.Affinity
+		wantErrs int
+	}{
+		{
+			name:     "nil affinity is valid",
+			affinity: nil,
+			wantErrs: 0,
+		},
+		{
+			name: "valid node affinity",
+			affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+							}},
+						},
+					},
+				},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "In requires at least one value",
+			affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "zone", Operator: api.NodeSelectorOpIn},
+							}},
+						},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "Exists must not specify values",
+			affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "zone", Operator: api.NodeSelectorOpExists, Values: []string{"us-east-1"}},
+							}},
+						},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "Gt requires exactly one integer value",
+			affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "cpus", Operator: api.NodeSelectorOpGt, Values: []string{"not-a-number"}},
+							}},
+						},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "In and NotIn with overlapping values is contradictory",
+			affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{
+								{Key: "zone", Operator: api.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+								{Key: "zone", Operator: api.NodeSelectorOpNotIn, Values: []string{"us-east-1"}},
+							}},
+						},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "required PodAffinity term without topology key",
+			affinity: &api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []api.PodAffinityTerm{
+						{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"svc": "s1"}}},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "preferred weight out of range",
+			affinity: &api.Affinity{
+				PodAntiAffinity: &api.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []api.WeightedPodAffinityTerm{
+						{Weight: 150, PodAffinityTerm: api.PodAffinityTerm{TopologyKey: "zone"}},
+					},
+				},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateAffinity(test.affinity)
+			if len(errs) != test.wantErrs {
+				t.Errorf("ValidateAffinity() = %v errors (%v), want %d", len(errs), errs, test.wantErrs)
+			}
+		})
+	}
+}